@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"strconv"
+	"time"
+)
+
+// flagRunAddr содержит адрес и порт, на котором consumer отдаёт GET /ws — пуш
+// подключённым клиентам идёт отсюда, а не из cmd/skill, потому что именно здесь
+// сообщение становится durably сохранённым (см. saveAndNotify в main.go).
+var flagRunAddr string
+
+// flagDatabaseDSN содержит строку соединения с PostgreSQL.
+var flagDatabaseDSN string
+
+// flagBrokerKind выбирает реализацию broker.Broker: memory, nats или kafka.
+var flagBrokerKind string
+
+// flagBatchSize — максимальное число сообщений в одном батче сохранения.
+var flagBatchSize int
+
+// flagBatchInterval — интервал, с которым батч сохраняется, даже если не набрался целиком.
+var flagBatchInterval time.Duration
+
+// parseFlags обрабатывает аргументы командной строки и переменные окружения.
+func parseFlags() {
+	flag.StringVar(&flagRunAddr, "a", ":8081", "address and port to serve GET /ws on")
+	flag.StringVar(&flagDatabaseDSN, "d", "", "database DSN")
+	flag.StringVar(&flagBrokerKind, "b", "memory", "broker kind: memory, nats or kafka")
+	flag.IntVar(&flagBatchSize, "batch-size", 100, "max number of messages per save batch")
+	flag.DurationVar(&flagBatchInterval, "batch-interval", 10*time.Second, "interval between batch saves")
+	flag.Parse()
+
+	if envRunAddr := os.Getenv("RUN_ADDR"); envRunAddr != "" {
+		flagRunAddr = envRunAddr
+	}
+	if envDatabaseDSN := os.Getenv("DATABASE_DSN"); envDatabaseDSN != "" {
+		flagDatabaseDSN = envDatabaseDSN
+	}
+	if envBrokerKind := os.Getenv("BROKER_KIND"); envBrokerKind != "" {
+		flagBrokerKind = envBrokerKind
+	}
+	if envBatchSize := os.Getenv("BATCH_SIZE"); envBatchSize != "" {
+		if v, err := strconv.Atoi(envBatchSize); err == nil {
+			flagBatchSize = v
+		}
+	}
+	if envBatchInterval := os.Getenv("BATCH_INTERVAL"); envBatchInterval != "" {
+		if v, err := time.ParseDuration(envBatchInterval); err == nil {
+			flagBatchInterval = v
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Command consumer читает сообщения из Broker, копит их в батчи и сохраняет в store.Store.
+package main
+
+import (
+	"context"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/shulganew/alice-skill.git/internal/broker"
+	"github.com/shulganew/alice-skill.git/internal/broker/factory"
+	"github.com/shulganew/alice-skill.git/internal/delivery"
+	"github.com/shulganew/alice-skill.git/internal/logger"
+	"github.com/shulganew/alice-skill.git/internal/store"
+	"github.com/shulganew/alice-skill.git/internal/store/postgres"
+	"github.com/shulganew/alice-skill.git/internal/wsserver"
+	"go.uber.org/zap"
+)
+
+// deliveryWorkers — размер пула воркеров, доставляющих события подписчикам.
+const deliveryWorkers = 8
+
+// sweepInterval — как часто повторять неудавшиеся доставки.
+const sweepInterval = 30 * time.Second
+
+func main() {
+	parseFlags()
+
+	if err := logger.Initialize("INFO"); err != nil {
+		panic(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	s, err := postgres.NewStore(ctx, flagDatabaseDSN)
+	if err != nil {
+		logger.Log.Fatal("cannot initialize storage", zap.Error(err))
+	}
+
+	if err := factory.RequireDurable(flagBrokerKind); err != nil {
+		logger.Log.Fatal("refusing to start", zap.Error(err))
+	}
+
+	b, err := factory.New(flagBrokerKind, "alice-skill-consumer")
+	if err != nil {
+		logger.Log.Fatal("cannot initialize broker", zap.Error(err))
+	}
+
+	dispatcher := delivery.NewDispatcher(s, deliveryWorkers)
+	go dispatcher.Sweep(ctx, sweepInterval)
+
+	// hub толкает сообщения WebSocket-клиентам только из этого процесса — он единственный,
+	// кто знает, что сообщение уже сохранено (см. saveAndNotify ниже), в отличие от
+	// webhook-процесса cmd/skill, видящего лишь сырую публикацию в очередь.
+	hub := wsserver.NewHub()
+
+	saveAndNotify := func(ctx context.Context, messages ...store.Message) ([]store.Message, error) {
+		saved, err := s.SaveMessages(ctx, messages...)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range saved {
+			if err := dispatcher.Notify(ctx, m); err != nil {
+				logger.Log.Debug("cannot notify subscribers", zap.Error(err))
+			}
+			hub.Broadcast(m.Recepient, m)
+		}
+
+		return saved, nil
+	}
+
+	consumer := broker.NewConsumer(saveAndNotify, flagBatchSize, flagBatchInterval)
+	go consumer.Run(ctx)
+
+	mux := http.NewServeMux()
+	mux.Handle("/ws", wsserver.Handler(hub, s.VerifyUserToken))
+	go func() {
+		logger.Log.Info("serving websocket push", zap.String("address", flagRunAddr))
+		if err := http.ListenAndServe(flagRunAddr, mux); err != nil {
+			logger.Log.Fatal("websocket server failed", zap.Error(err))
+		}
+	}()
+
+	logger.Log.Info("running consumer", zap.String("broker", flagBrokerKind))
+	if err := b.Subscribe(ctx, consumer.Handler()); err != nil {
+		logger.Log.Info("consumer stopped", zap.Error(err))
+	}
+}
@@ -2,16 +2,16 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
-	"strings"
+	"strconv"
 	"time"
 
+	"github.com/shulganew/alice-skill.git/internal/assistant"
+	"github.com/shulganew/alice-skill.git/internal/broker"
 	"github.com/shulganew/alice-skill.git/internal/logger"
-	"github.com/shulganew/alice-skill.git/internal/logger/services/parser"
-	"github.com/shulganew/alice-skill.git/internal/models"
+	"github.com/shulganew/alice-skill.git/internal/nlu"
 	"github.com/shulganew/alice-skill.git/internal/store"
 	"go.uber.org/zap"
 )
@@ -19,85 +19,105 @@ import (
 // app инкапсулирует в себя все зависимости и логику приложения.
 type app struct {
 	store store.Store
-	// канал для отложенной отправки новых сообщений
-	msgChan chan store.Message
+	// очередь отложенной отправки новых сообщений на сохранение
+	broker broker.Broker
+	// распознаёт интент и слоты в тексте команды вместо сравнения префиксов
+	recognizer nlu.Recognizer
 }
 
-func newApp(s store.Store) *app {
-	instance := &app{
-		store:   s,
-		msgChan: make(chan store.Message, 1024), // установим каналу буфер в 1024 сообщения
+func newApp(s store.Store, b broker.Broker, r nlu.Recognizer) *app {
+	return &app{
+		store:      s,
+		broker:     b,
+		recognizer: r,
 	}
-
-	// запустим горутину с фоновым сохранением новых сообщений
-	go instance.flushMessages()
-
-	return instance
 }
 
-func (a *app) webhook(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+// webhookHandler возвращает HTTP-обработчик для конкретной платформы голосового
+// ассистента: он декодирует запрос через platform, прогоняет его через platform-agnostic
+// a.handle и кодирует ответ обратно через platform.
+func (a *app) webhookHandler(platform assistant.Platform) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			logger.Log.Debug("got request with bad method", zap.String("method", r.Method))
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
 
-	if r.Method != http.MethodPost {
-		logger.Log.Debug("got request with bad method", zap.String("method", r.Method))
-		w.WriteHeader(http.StatusMethodNotAllowed)
-		return
-	}
+		logger.Log.Debug("decoding request")
+		utterance, err := platform.Decode(r)
+		if err != nil {
+			logger.Log.Debug("cannot decode request", zap.Error(err))
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
 
-	logger.Log.Debug("decoding request")
-	var req models.Request
-	dec := json.NewDecoder(r.Body)
-	if err := dec.Decode(&req); err != nil {
-		logger.Log.Debug("cannot decode request JSON body", zap.Error(err))
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
+		reply, status := a.handle(r.Context(), utterance)
+		if status != 0 {
+			w.WriteHeader(status)
+			return
+		}
 
-	if req.Request.Type != models.TypeSimpleUtterance {
-		logger.Log.Debug("unsupported request type", zap.String("type", req.Request.Type))
-		w.WriteHeader(http.StatusUnprocessableEntity)
-		return
+		if err := platform.Encode(w, reply); err != nil {
+			logger.Log.Debug("error encoding response", zap.Error(err))
+			return
+		}
+		logger.Log.Debug("sending HTTP 200 response")
 	}
+}
 
-	// текст ответа навыка
+// handle выполняет основную логику навыка над нормализованным Utterance, не зная
+// ничего о протоколе конкретного голосового ассистента. Ненулевой status означает,
+// что запрос нужно прервать с этим HTTP-статусом вместо обычного ответа.
+func (a *app) handle(ctx context.Context, utterance assistant.Utterance) (reply assistant.Reply, status int) {
 	var text string
 
-	switch true {
+	intent, err := a.recognizer.Recognize(utterance)
+	if err != nil {
+		logger.Log.Debug("cannot recognize intent", zap.Error(err))
+		return assistant.Reply{}, http.StatusInternalServerError
+	}
+
+	switch intent.Name {
 	// пользователь попросил отправить сообщение
-	case strings.HasPrefix(req.Request.Command, "Отправь"):
-		// гипотетическая функция parseSendCommand вычленит из запроса логин адресата и текст сообщения
-		username, message := parser.ParseSendCommand(req.Request.Command)
+	case nlu.IntentSend:
+		username, message := intent.Slots["user"], intent.Slots["text"]
 
 		// найдём внутренний идентификатор адресата по его логину
 		recipientID, err := a.store.FindRecipient(ctx, username)
 		if err != nil {
 			logger.Log.Debug("cannot find recipient by username", zap.String("username", username), zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			return
+			return assistant.Reply{}, http.StatusInternalServerError
 		}
 
-		// отправим сообщение в очередь на сохранение
-		a.msgChan <- store.Message{
-			Sender:    req.Session.User.UserID,
+		// опубликуем сообщение в очередь на сохранение
+		err = a.broker.Publish(ctx, store.Message{
+			Sender:    utterance.UserID,
 			Recepient: recipientID,
 			Time:      time.Now(),
 			Payload:   message,
+		})
+		if err != nil {
+			logger.Log.Debug("cannot publish message", zap.Error(err))
+			return assistant.Reply{}, http.StatusInternalServerError
 		}
 
 		// Оповестим отправителя об успешности операции
 		text = "Сообщение успешно отправлено"
 
 	// пользователь попросил прочитать сообщение
-	case strings.HasPrefix(req.Request.Command, "Прочитай"):
-		// гипотетическая функция parseReadCommand вычленит из запроса порядковый номер сообщения в списке доступных
-		messageIndex := parser.ParseReadCommand(req.Request.Command)
+	case nlu.IntentRead:
+		// порядковый номер сообщения в списке доступных; если не назвали — читаем первое
+		messageIndex := 0
+		if index, ok := intent.Slots["index"]; ok {
+			messageIndex, _ = strconv.Atoi(index)
+		}
 
 		// получим список непрослушанных сообщений пользователя
-		messages, err := a.store.ListMessages(ctx, req.Session.User.UserID)
+		messages, err := a.store.ListMessages(ctx, utterance.UserID)
 		if err != nil {
 			logger.Log.Debug("cannot load messages for user", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			return
+			return assistant.Reply{}, http.StatusInternalServerError
 		}
 
 		text = "Для вас нет новых сообщений."
@@ -110,43 +130,49 @@ func (a *app) webhook(w http.ResponseWriter, r *http.Request) {
 			message, err := a.store.GetMessage(ctx, messageID)
 			if err != nil {
 				logger.Log.Debug("cannot load message", zap.Int64("id", messageID), zap.Error(err))
-				w.WriteHeader(http.StatusInternalServerError)
-				return
+				return assistant.Reply{}, http.StatusInternalServerError
 			}
 
 			// передадим текст сообщения в ответе
 			text = fmt.Sprintf("Сообщение от %s, отправлено %s: %s", message.Sender, message.Time, message.Payload)
 		}
 
-		// пользователь хочет зарегистрироваться
-	case strings.HasPrefix(req.Request.Command, "Зарегистрируй"):
-		// гипотетическая функция parseRegisterCommand вычленит из запроса
-		// желаемое имя нового пользователя
-		username := parser.ParseRegisterCommand(req.Request.Command)
+	// пользователь хочет зарегистрироваться
+	case nlu.IntentRegister:
+		username := intent.Slots["user"]
 
-		// регистрируем пользователя
-		err := a.store.RegisterUser(ctx, req.Session.User.UserID, username)
+		// регистрируем пользователя и получаем токен для WebSocket-подключений (см. internal/wsserver)
+		token, err := a.store.RegisterUser(ctx, utterance.UserID, username)
 		// наличие неспецифичной ошибки
 		if err != nil && !errors.Is(err, store.ErrConflict) {
 			logger.Log.Debug("cannot register user", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			return
+			return assistant.Reply{}, http.StatusInternalServerError
 		}
 
 		// определяем правильное ответное сообщение пользователю
-		text = fmt.Sprintf("Вы успешно зарегистрированы под именем %s", username)
+		text = fmt.Sprintf("Вы успешно зарегистрированы под именем %s. Токен для подключения: %s", username, token)
 		if errors.Is(err, store.ErrConflict) {
 			// ошибка специфична для случая конфликта имён пользователей
 			text = "Извините, такое имя уже занято. Попробуйте другое."
 		}
 
+	// пользователь хочет подписаться на доставку новых сообщений по HTTP
+	case nlu.IntentSubscribe:
+		url, secret := intent.Slots["url"], intent.Slots["secret"]
+
+		if _, err := a.store.AddSubscription(ctx, utterance.UserID, url, secret); err != nil {
+			logger.Log.Debug("cannot add subscription", zap.Error(err))
+			return assistant.Reply{}, http.StatusInternalServerError
+		}
+
+		text = "Подписка успешно оформлена"
+
 	// если не поняли команду, просто скажем пользователю, сколько у него новых сообщений
 	default:
-		messages, err := a.store.ListMessages(ctx, req.Session.User.UserID)
+		messages, err := a.store.ListMessages(ctx, utterance.UserID)
 		if err != nil {
 			logger.Log.Debug("cannot load messages for user", zap.Error(err))
-			w.WriteHeader(http.StatusInternalServerError)
-			return
+			return assistant.Reply{}, http.StatusInternalServerError
 		}
 
 		text = "Для вас нет новых сообщений."
@@ -155,13 +181,12 @@ func (a *app) webhook(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// первый запрос новой сессии
-		if req.Session.New {
-			// обработаем поле Timezone запроса
-			tz, err := time.LoadLocation(req.Timezone)
+		if utterance.SessionNew {
+			// обработаем часовой пояс пользователя
+			tz, err := time.LoadLocation(utterance.Timezone)
 			if err != nil {
 				logger.Log.Debug("cannot parse timezone")
-				w.WriteHeader(http.StatusBadRequest)
-				return
+				return assistant.Reply{}, http.StatusBadRequest
 			}
 
 			// получим текущее время в часовом поясе пользователя
@@ -173,51 +198,5 @@ func (a *app) webhook(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// заполним модель ответа
-	resp := models.Response{
-		Response: models.ResponsePayload{
-			Text: text, // Алиса проговорит текст
-		},
-		Version: "1.0",
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-
-	// сериализуем ответ сервера
-	enc := json.NewEncoder(w)
-	if err := enc.Encode(resp); err != nil {
-		logger.Log.Debug("error encoding response", zap.Error(err))
-		return
-	}
-	logger.Log.Debug("sending HTTP 200 response")
-}
-
-// flushMessages постоянно сохраняет несколько сообщений в хранилище с определённым интервалом
-func (a *app) flushMessages() {
-	// будем сохранять сообщения, накопленные за последние 10 секунд
-	ticker := time.NewTicker(10 * time.Second)
-
-	var messages []store.Message
-
-	for {
-		select {
-		case msg := <-a.msgChan:
-			// добавим сообщение в слайс для последующего сохранения
-			messages = append(messages, msg)
-		case <-ticker.C:
-			// подождём, пока придёт хотя бы одно сообщение
-			if len(messages) == 0 {
-				continue
-			}
-			// сохраним все пришедшие сообщения одновременно
-			err := a.store.SaveMessages(context.TODO(), messages...)
-			if err != nil {
-				logger.Log.Debug("cannot save messages", zap.Error(err))
-				// не будем стирать сообщения, попробуем отправить их чуть позже
-				continue
-			}
-			// сотрём успешно отосланные сообщения
-			messages = nil
-		}
-	}
+	return assistant.Reply{Text: text}, 0
 }
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"os"
+)
+
+// flagRunAddr содержит адрес и порт для запуска сервера.
+var flagRunAddr string
+
+// flagDatabaseDSN содержит строку соединения с PostgreSQL.
+var flagDatabaseDSN string
+
+// flagBrokerKind выбирает реализацию broker.Broker: memory, nats или kafka.
+var flagBrokerKind string
+
+// flagDebugToken — bearer-токен, защищающий отладочный эндпоинт GET /subscriptions/:userID.
+var flagDebugToken string
+
+// parseFlags обрабатывает аргументы командной строки и переменные окружения.
+func parseFlags() {
+	flag.StringVar(&flagRunAddr, "a", ":8080", "address and port to run server")
+	flag.StringVar(&flagDatabaseDSN, "d", "", "database DSN")
+	flag.StringVar(&flagBrokerKind, "b", "memory", "broker kind: memory, nats or kafka")
+	flag.StringVar(&flagDebugToken, "t", "", "bearer token protecting the debug subscriptions endpoint")
+	flag.Parse()
+
+	if envRunAddr := os.Getenv("RUN_ADDR"); envRunAddr != "" {
+		flagRunAddr = envRunAddr
+	}
+	if envDatabaseDSN := os.Getenv("DATABASE_DSN"); envDatabaseDSN != "" {
+		flagDatabaseDSN = envDatabaseDSN
+	}
+	if envBrokerKind := os.Getenv("BROKER_KIND"); envBrokerKind != "" {
+		flagBrokerKind = envBrokerKind
+	}
+	if envDebugToken := os.Getenv("DEBUG_TOKEN"); envDebugToken != "" {
+		flagDebugToken = envDebugToken
+	}
+}
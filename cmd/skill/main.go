@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/shulganew/alice-skill.git/internal/assistant/alice"
+	"github.com/shulganew/alice-skill.git/internal/assistant/dialogflow"
+	"github.com/shulganew/alice-skill.git/internal/assistant/marusia"
+	"github.com/shulganew/alice-skill.git/internal/broker/factory"
+	"github.com/shulganew/alice-skill.git/internal/logger"
+	"github.com/shulganew/alice-skill.git/internal/nlu"
+	"github.com/shulganew/alice-skill.git/internal/nlu/rulebased"
+	"github.com/shulganew/alice-skill.git/internal/nlu/yandex"
+	"github.com/shulganew/alice-skill.git/internal/store/postgres"
+	"go.uber.org/zap"
+)
+
+func main() {
+	parseFlags()
+
+	if err := logger.Initialize("INFO"); err != nil {
+		panic(err)
+	}
+
+	ctx := context.Background()
+
+	s, err := postgres.NewStore(ctx, flagDatabaseDSN)
+	if err != nil {
+		logger.Log.Fatal("cannot initialize storage", zap.Error(err))
+	}
+
+	if err := factory.RequireDurable(flagBrokerKind); err != nil {
+		logger.Log.Fatal("refusing to start", zap.Error(err))
+	}
+
+	b, err := factory.New(flagBrokerKind, "")
+	if err != nil {
+		logger.Log.Fatal("cannot initialize broker", zap.Error(err))
+	}
+
+	// сперва пробуем встроенную NLU Алисы и откатываемся на собственные регэкспы,
+	// если платформа не прислала разметку или не распознала интент
+	recognizer := nlu.Fallback(yandex.New(), rulebased.New())
+	appInstance := newApp(s, b, recognizer)
+
+	mux := http.NewServeMux()
+	mux.Handle("/alice", appInstance.webhookHandler(alice.New()))
+	mux.Handle("/marusia", appInstance.webhookHandler(marusia.New()))
+	mux.Handle("/dialogflow", appInstance.webhookHandler(dialogflow.New()))
+	mux.Handle("/subscriptions/", appInstance.subscriptionsHandler(flagDebugToken))
+
+	logger.Log.Info("running server", zap.String("address", flagRunAddr))
+	if err := http.ListenAndServe(flagRunAddr, mux); err != nil {
+		logger.Log.Fatal("server failed", zap.Error(err))
+	}
+}
@@ -8,6 +8,9 @@ import (
 
 	"github.com/go-resty/resty/v2"
 	"github.com/golang/mock/gomock"
+	"github.com/shulganew/alice-skill.git/internal/assistant/alice"
+	"github.com/shulganew/alice-skill.git/internal/broker/memory"
+	"github.com/shulganew/alice-skill.git/internal/nlu/rulebased"
 	"github.com/shulganew/alice-skill.git/internal/store"
 	"github.com/shulganew/alice-skill.git/internal/store/mock"
 	"github.com/stretchr/testify/assert"
@@ -32,10 +35,10 @@ func TestWebhook(t *testing.T) {
 		ListMessages(gomock.Any(), gomock.Any()).
 		Return(messages, nil)
 
-	// создаём экземпляр приложения и передаём ему «хранилище»
-	appInstance := newApp(s)
+	// создаём экземпляр приложения и передаём ему «хранилище» и брокер очереди сообщений
+	appInstance := newApp(s, memory.NewBroker(1024), rulebased.New())
 
-	handler := http.HandlerFunc(appInstance.webhook)
+	handler := appInstance.webhookHandler(alice.New())
 	srv := httptest.NewServer(handler)
 	defer srv.Close()
 
@@ -67,7 +70,7 @@ func TestWebhook(t *testing.T) {
 		{
 			name:         "method_post_without_body",
 			method:       http.MethodPost,
-			expectedCode: http.StatusInternalServerError,
+			expectedCode: http.StatusUnprocessableEntity,
 			expectedBody: "",
 		},
 		{
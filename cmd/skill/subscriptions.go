@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/shulganew/alice-skill.git/internal/logger"
+	"go.uber.org/zap"
+)
+
+// subscriptionsHandler отдаёт список подписок пользователя для отладки. Доступ
+// защищён bearer-токеном из конфигурации.
+func (a *app) subscriptionsHandler(token string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		const bearerPrefix = "Bearer "
+		auth := r.Header.Get("Authorization")
+		if token == "" || !strings.HasPrefix(auth, bearerPrefix) || strings.TrimPrefix(auth, bearerPrefix) != token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		userID := strings.TrimPrefix(r.URL.Path, "/subscriptions/")
+		if userID == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		subs, err := a.store.ListSubscriptions(r.Context(), userID)
+		if err != nil {
+			logger.Log.Debug("cannot list subscriptions", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(subs); err != nil {
+			logger.Log.Debug("error encoding response", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Package alice реализует assistant.Platform для навыков Яндекс.Алисы.
+package alice
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/shulganew/alice-skill.git/internal/assistant"
+	"github.com/shulganew/alice-skill.git/internal/models"
+)
+
+// ErrUnsupportedType возвращается, когда Алиса прислала запрос неизвестного типа.
+var ErrUnsupportedType = errors.New("unsupported request type")
+
+// Platform — реализация assistant.Platform для Яндекс.Алисы.
+type Platform struct{}
+
+// New создаёт Platform для Яндекс.Алисы.
+func New() *Platform {
+	return &Platform{}
+}
+
+// Decode разбирает запрос Алисы в нормализованный assistant.Utterance.
+func (p *Platform) Decode(r *http.Request) (assistant.Utterance, error) {
+	var req models.Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return assistant.Utterance{}, err
+	}
+
+	if req.Request.Type != models.TypeSimpleUtterance {
+		return assistant.Utterance{}, ErrUnsupportedType
+	}
+
+	return assistant.Utterance{
+		UserID:     req.Session.User.UserID,
+		Text:       req.Request.Command,
+		Timezone:   req.Timezone,
+		SessionNew: req.Session.New,
+		Nlu:        decodeNlu(req.Request.Nlu),
+	}, nil
+}
+
+// decodeNlu переносит встроенную NLU-разметку Алисы в платформо-независимый вид.
+// Возвращает nil, если Алиса не прислала ни одного распознанного интента.
+func decodeNlu(nlu models.Nlu) *assistant.NluHint {
+	if len(nlu.Intents) == 0 {
+		return nil
+	}
+
+	hint := &assistant.NluHint{
+		Tokens:  nlu.Tokens,
+		Intents: make(map[string]assistant.NluIntent, len(nlu.Intents)),
+	}
+
+	for name, intent := range nlu.Intents {
+		slots := make(map[string]assistant.NluSlot, len(intent.Slots))
+		for slotName, slot := range intent.Slots {
+			slots[slotName] = assistant.NluSlot{
+				Type:  slot.Type,
+				Value: fmt.Sprint(slot.Value),
+			}
+		}
+		hint.Intents[name] = assistant.NluIntent{Slots: slots}
+	}
+
+	return hint
+}
+
+// Encode сериализует Reply в ответ в формате Алисы.
+func (p *Platform) Encode(w http.ResponseWriter, reply assistant.Reply) error {
+	resp := models.Response{
+		Response: models.ResponsePayload{
+			Text: reply.Text,
+		},
+		Version: "1.0",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,59 @@
+package alice
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shulganew/alice-skill.git/internal/assistant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlatform_Decode(t *testing.T) {
+	testCases := []struct {
+		name    string
+		body    string
+		want    assistant.Utterance
+		wantErr bool
+	}{
+		{
+			// пример из документации Яндекс.Диалогов
+			name: "simple_utterance",
+			body: `{
+				"request": {"command": "закажи пиццу", "original_utterance": "закажи пиццу", "type": "SimpleUtterance"},
+				"session": {"new": true, "user": {"user_id": "411419e5-f5be-4cdb-83aa-2ca2b6648353"}},
+				"timezone": "UTC",
+				"version": "1.0"
+			}`,
+			want: assistant.Utterance{
+				UserID:     "411419e5-f5be-4cdb-83aa-2ca2b6648353",
+				Text:       "закажи пиццу",
+				Timezone:   "UTC",
+				SessionNew: true,
+			},
+		},
+		{
+			name:    "unsupported_type",
+			body:    `{"request": {"type": "ButtonPressed", "command": ""}, "version": "1.0"}`,
+			wantErr: true,
+		},
+	}
+
+	p := New()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/alice", strings.NewReader(tc.body))
+
+			got, err := p.Decode(r)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
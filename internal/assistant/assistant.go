@@ -0,0 +1,58 @@
+// Package assistant абстрагирует протокол голосового ассистента (Алиса, Марися,
+// Google Assistant) от основной логики навыка.
+package assistant
+
+import "net/http"
+
+// Utterance — нормализованное представление одной голосовой команды пользователя,
+// не зависящее от конкретной платформы.
+type Utterance struct {
+	// UserID — внутренний идентификатор пользователя на стороне платформы.
+	UserID string
+	// Text — произнесённая пользователем команда.
+	Text string
+	// Locale — язык пользователя, например "ru-RU".
+	Locale string
+	// Timezone — часовой пояс пользователя, если платформа его передаёт.
+	Timezone string
+	// SessionNew истинно для первого запроса новой сессии.
+	SessionNew bool
+	// Nlu — разметка интентов и слотов, которую успела сделать сама платформа
+	// (сейчас её присылает только Яндекс.Алиса). nil, если платформа такого не умеет
+	// или ничего не распознала — тогда internal/nlu разбирает Text самостоятельно.
+	Nlu *NluHint
+}
+
+// NluHint — встроенная NLU-разметка запроса платформы.
+type NluHint struct {
+	// Tokens — текст, разбитый платформой на токены.
+	Tokens []string
+	// Intents — распознанные платформой интенты по их именам.
+	Intents map[string]NluIntent
+}
+
+// NluIntent — один интент, распознанный платформой, вместе со слотами.
+type NluIntent struct {
+	Slots map[string]NluSlot
+}
+
+// NluSlot — значение одного слота интента.
+type NluSlot struct {
+	Type  string
+	Value string
+}
+
+// Reply — ответ навыка, который предстоит закодировать в формат конкретной платформы.
+type Reply struct {
+	// Text — текст, который озвучит или покажет ассистент.
+	Text string
+}
+
+// Platform декодирует входящий запрос конкретного голосового ассистента в Utterance
+// и кодирует Reply обратно в ответ, понятный этому ассистенту.
+type Platform interface {
+	// Decode разбирает HTTP-запрос в нормализованный Utterance.
+	Decode(r *http.Request) (Utterance, error)
+	// Encode сериализует Reply в HTTP-ответ в формате платформы.
+	Encode(w http.ResponseWriter, reply Reply) error
+}
@@ -0,0 +1,63 @@
+// Package dialogflow реализует assistant.Platform для Google Assistant через
+// вебхук фулфилмента Dialogflow: https://cloud.google.com/dialogflow/es/docs/fulfillment-webhook.
+package dialogflow
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/shulganew/alice-skill.git/internal/assistant"
+)
+
+// request — тело запроса вебхука фулфилмента Dialogflow (используемые поля).
+type request struct {
+	QueryResult struct {
+		QueryText      string `json:"queryText"`
+		LanguageCode   string `json:"languageCode"`
+		OutputContexts []any  `json:"outputContexts"`
+	} `json:"queryResult"`
+	// Session имеет вид "projects/<project>/agent/sessions/<session-id>" и однозначно
+	// идентифицирует диалоговую сессию; Dialogflow не передаёт отдельный user ID
+	// в базовом запросе, поэтому используем его как UserID.
+	Session string `json:"session"`
+}
+
+// response — тело ответа, которое ожидает Dialogflow.
+type response struct {
+	FulfillmentText string `json:"fulfillmentText"`
+}
+
+// Platform — реализация assistant.Platform для Google Assistant.
+type Platform struct{}
+
+// New создаёт Platform для Google Assistant.
+func New() *Platform {
+	return &Platform{}
+}
+
+// Decode разбирает запрос Dialogflow в нормализованный assistant.Utterance.
+//
+// Dialogflow не передаёт явный признак новой сессии и часовой пояс пользователя,
+// поэтому SessionNew определяется эвристически по отсутствию output-контекстов,
+// а Timezone остаётся пустым.
+func (p *Platform) Decode(r *http.Request) (assistant.Utterance, error) {
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return assistant.Utterance{}, err
+	}
+
+	return assistant.Utterance{
+		UserID:     req.Session,
+		Text:       req.QueryResult.QueryText,
+		Locale:     req.QueryResult.LanguageCode,
+		SessionNew: len(req.QueryResult.OutputContexts) == 0,
+	}, nil
+}
+
+// Encode сериализует Reply в ответ в формате Dialogflow.
+func (p *Platform) Encode(w http.ResponseWriter, reply assistant.Reply) error {
+	resp := response{FulfillmentText: reply.Text}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,68 @@
+package dialogflow
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shulganew/alice-skill.git/internal/assistant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlatform_Decode(t *testing.T) {
+	testCases := []struct {
+		name string
+		body string
+		want assistant.Utterance
+	}{
+		{
+			// пример из документации Dialogflow: https://cloud.google.com/dialogflow/es/docs/fulfillment-webhook
+			name: "new_session",
+			body: `{
+				"responseId": "response-id",
+				"session": "projects/my-agent/agent/sessions/session-id",
+				"queryResult": {
+					"queryText": "book a table",
+					"languageCode": "en",
+					"outputContexts": []
+				}
+			}`,
+			want: assistant.Utterance{
+				UserID:     "projects/my-agent/agent/sessions/session-id",
+				Text:       "book a table",
+				Locale:     "en",
+				SessionNew: true,
+			},
+		},
+		{
+			name: "continuing_session",
+			body: `{
+				"session": "projects/my-agent/agent/sessions/session-id",
+				"queryResult": {
+					"queryText": "yes",
+					"languageCode": "en",
+					"outputContexts": [{"name": "projects/my-agent/agent/sessions/session-id/contexts/awaiting-confirmation"}]
+				}
+			}`,
+			want: assistant.Utterance{
+				UserID:     "projects/my-agent/agent/sessions/session-id",
+				Text:       "yes",
+				Locale:     "en",
+				SessionNew: false,
+			},
+		},
+	}
+
+	p := New()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/dialogflow", strings.NewReader(tc.body))
+
+			got, err := p.Decode(r)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
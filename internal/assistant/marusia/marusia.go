@@ -0,0 +1,89 @@
+// Package marusia реализует assistant.Platform для голосового ассистента VK «Марися».
+package marusia
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/shulganew/alice-skill.git/internal/assistant"
+)
+
+// ErrUnsupportedType возвращается, когда Марися прислала запрос неизвестного типа.
+var ErrUnsupportedType = errors.New("unsupported request type")
+
+// typeSimpleUtterance — единственный пока поддерживаемый тип запроса.
+const typeSimpleUtterance = "SimpleUtterance"
+
+// request — тело запроса от Марисьи. Схема почти совпадает с Алисой,
+// но своя: https://vk.com/dev/marusia_skill_docs8.
+type request struct {
+	Meta struct {
+		Locale   string `json:"locale"`
+		Timezone string `json:"timezone"`
+	} `json:"meta"`
+	Request struct {
+		Type    string `json:"type"`
+		Command string `json:"command"`
+	} `json:"request"`
+	Session struct {
+		SessionID string `json:"session_id"`
+		MessageID int    `json:"message_id"`
+		UserID    string `json:"user_id"`
+		New       bool   `json:"new"`
+	} `json:"session"`
+	Version string `json:"version"`
+}
+
+// response — тело ответа, который ожидает Марися.
+type response struct {
+	Response struct {
+		Text string `json:"text"`
+		TTS  string `json:"tts"`
+	} `json:"response"`
+	Session struct {
+		SessionID string `json:"session_id"`
+		MessageID int    `json:"message_id"`
+		UserID    string `json:"user_id"`
+	} `json:"session"`
+	Version string `json:"version"`
+}
+
+// Platform — реализация assistant.Platform для Марисьи.
+type Platform struct{}
+
+// New создаёт Platform для Марисьи.
+func New() *Platform {
+	return &Platform{}
+}
+
+// Decode разбирает запрос Марисьи в нормализованный assistant.Utterance.
+func (p *Platform) Decode(r *http.Request) (assistant.Utterance, error) {
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return assistant.Utterance{}, err
+	}
+
+	if req.Request.Type != typeSimpleUtterance {
+		return assistant.Utterance{}, ErrUnsupportedType
+	}
+
+	return assistant.Utterance{
+		UserID:     req.Session.UserID,
+		Text:       req.Request.Command,
+		Locale:     req.Meta.Locale,
+		Timezone:   req.Meta.Timezone,
+		SessionNew: req.Session.New,
+	}, nil
+}
+
+// Encode сериализует Reply в ответ в формате Марисьи.
+func (p *Platform) Encode(w http.ResponseWriter, reply assistant.Reply) error {
+	var resp response
+	resp.Response.Text = reply.Text
+	resp.Response.TTS = reply.Text
+	resp.Version = "1.0"
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,60 @@
+package marusia
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shulganew/alice-skill.git/internal/assistant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlatform_Decode(t *testing.T) {
+	testCases := []struct {
+		name    string
+		body    string
+		want    assistant.Utterance
+		wantErr bool
+	}{
+		{
+			// пример из документации VK: https://vk.com/dev/marusia_skill_docs8
+			name: "simple_utterance",
+			body: `{
+				"meta": {"locale": "ru-RU", "timezone": "Europe/Moscow"},
+				"request": {"command": "расскажи анекдот", "original_utterance": "расскажи анекдот", "type": "SimpleUtterance"},
+				"session": {"session_id": "2eac4820-.....", "message_id": 0, "user_id": "1519568535902841890", "new": true},
+				"version": "1.0"
+			}`,
+			want: assistant.Utterance{
+				UserID:     "1519568535902841890",
+				Text:       "расскажи анекдот",
+				Locale:     "ru-RU",
+				Timezone:   "Europe/Moscow",
+				SessionNew: true,
+			},
+		},
+		{
+			name:    "unsupported_type",
+			body:    `{"request": {"type": "ButtonPressed", "command": ""}, "version": "1.0"}`,
+			wantErr: true,
+		},
+	}
+
+	p := New()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/marusia", strings.NewReader(tc.body))
+
+			got, err := p.Decode(r)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+// Package broker описывает абстракцию очереди исходящих сообщений и её реализации.
+package broker
+
+import (
+	"context"
+
+	"github.com/shulganew/alice-skill.git/internal/store"
+)
+
+// Handler обрабатывает одно доставленное сообщение. Возврат nil подтверждает (ack) сообщение,
+// возврат ошибки оставляет его для повторной доставки.
+type Handler func(ctx context.Context, msg store.Message) error
+
+// Broker — абстрактная очередь, через которую проходят исходящие сообщения
+// между приёмом вебхука и сохранением в store.Store.
+type Broker interface {
+	// Publish публикует сообщение в очередь.
+	Publish(ctx context.Context, msg store.Message) error
+	// Subscribe подписывается на очередь и вызывает handler для каждого сообщения.
+	// Блокируется до отмены ctx.
+	Subscribe(ctx context.Context, handler Handler) error
+}
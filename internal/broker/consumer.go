@@ -0,0 +1,129 @@
+package broker
+
+import (
+	"context"
+	"time"
+
+	"github.com/shulganew/alice-skill.git/internal/logger"
+	"github.com/shulganew/alice-skill.git/internal/store"
+	"go.uber.org/zap"
+)
+
+// maxSaveAttempts — максимальное число попыток сохранить батч перед тем, как сдаться.
+const maxSaveAttempts = 5
+
+// initialSaveBackoff — задержка перед первым повтором сохранения батча, дальше растёт
+// экспоненциально. Var, а не const, чтобы тесты могли ускорить её и не ждать реальные секунды.
+var initialSaveBackoff = 100 * time.Millisecond
+
+// BatchSaver сохраняет накопленный батч сообщений и возвращает их же с присвоенными
+// хранилищем id, например store.Store.SaveMessages.
+type BatchSaver func(ctx context.Context, messages ...store.Message) ([]store.Message, error)
+
+// pending — сообщение, ожидающее сохранения в составе батча.
+type pending struct {
+	msg  store.Message
+	done chan error
+}
+
+// Consumer копит сообщения, полученные через Handler, в батчи заданного размера
+// или интервала и сохраняет их через BatchSaver. Подтверждение (ack) каждого
+// сообщения брокеру происходит только после успешного сохранения его батча,
+// что даёт семантику at-least-once.
+type Consumer struct {
+	save          BatchSaver
+	batchSize     int
+	batchInterval time.Duration
+
+	buf chan pending
+}
+
+// NewConsumer создаёт Consumer с заданными параметрами батчирования.
+func NewConsumer(save BatchSaver, batchSize int, batchInterval time.Duration) *Consumer {
+	return &Consumer{
+		save:          save,
+		batchSize:     batchSize,
+		batchInterval: batchInterval,
+		buf:           make(chan pending, batchSize*2),
+	}
+}
+
+// Handler возвращает Handler, который нужно передать в Broker.Subscribe.
+func (c *Consumer) Handler() Handler {
+	return func(ctx context.Context, msg store.Message) error {
+		p := pending{msg: msg, done: make(chan error, 1)}
+
+		select {
+		case c.buf <- p:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		select {
+		case err := <-p.done:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Run копит сообщения и периодически сохраняет их батчами, пока не отменят ctx.
+func (c *Consumer) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.batchInterval)
+	defer ticker.Stop()
+
+	var batch []pending
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		err := c.saveWithRetry(ctx, batch)
+		for _, p := range batch {
+			p.done <- err
+		}
+		batch = nil
+	}
+
+	for {
+		select {
+		case p := <-c.buf:
+			batch = append(batch, p)
+			if len(batch) >= c.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-ctx.Done():
+			flush()
+			return
+		}
+	}
+}
+
+// saveWithRetry пытается сохранить батч с экспоненциальной задержкой между попытками.
+func (c *Consumer) saveWithRetry(ctx context.Context, batch []pending) error {
+	messages := make([]store.Message, len(batch))
+	for i, p := range batch {
+		messages[i] = p.msg
+	}
+
+	backoff := initialSaveBackoff
+	var err error
+	for attempt := 1; attempt <= maxSaveAttempts; attempt++ {
+		if _, err = c.save(ctx, messages...); err == nil {
+			return nil
+		}
+		logger.Log.Debug("cannot save batch, retrying", zap.Int("attempt", attempt), zap.Error(err))
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+	}
+
+	return err
+}
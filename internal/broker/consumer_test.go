@@ -0,0 +1,126 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shulganew/alice-skill.git/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSaver — тестовый BatchSaver, который запоминает каждый переданный ему батч.
+func recordingSaver(batches *[][]store.Message, mu *sync.Mutex) BatchSaver {
+	return func(ctx context.Context, messages ...store.Message) ([]store.Message, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		*batches = append(*batches, append([]store.Message(nil), messages...))
+		return messages, nil
+	}
+}
+
+func TestConsumer_FlushesWhenBatchSizeReached(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]store.Message
+
+	c := NewConsumer(recordingSaver(&batches, &mu), 2, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.Run(ctx)
+
+	handler := c.Handler()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := handler(ctx, store.Message{Payload: fmt.Sprintf("msg-%d", i)})
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, batches, 1)
+	assert.Len(t, batches[0], 2)
+}
+
+func TestConsumer_FlushesOnInterval(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]store.Message
+
+	// batchSize большой, так что только тикер может вызвать flush
+	c := NewConsumer(recordingSaver(&batches, &mu), 100, 20*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.Run(ctx)
+
+	err := c.Handler()(ctx, store.Message{Payload: "hi"})
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, batches, 1)
+	assert.Len(t, batches[0], 1)
+}
+
+func TestConsumer_AcksOnlyAfterSaveSucceeds(t *testing.T) {
+	var mu sync.Mutex
+	attempts := 0
+
+	saver := func(ctx context.Context, messages ...store.Message) ([]store.Message, error) {
+		mu.Lock()
+		attempts++
+		n := attempts
+		mu.Unlock()
+		if n < 3 {
+			return nil, errors.New("transient failure")
+		}
+		return messages, nil
+	}
+
+	prevBackoff := initialSaveBackoff
+	initialSaveBackoff = time.Millisecond
+	defer func() { initialSaveBackoff = prevBackoff }()
+
+	c := NewConsumer(saver, 1, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.Run(ctx)
+
+	err := c.Handler()(ctx, store.Message{Payload: "hi"})
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 3, attempts)
+}
+
+func TestConsumer_NacksAfterExhaustingRetries(t *testing.T) {
+	prevBackoff := initialSaveBackoff
+	initialSaveBackoff = time.Millisecond
+	defer func() { initialSaveBackoff = prevBackoff }()
+
+	wantErr := errors.New("store is down")
+	saver := func(ctx context.Context, messages ...store.Message) ([]store.Message, error) {
+		return nil, wantErr
+	}
+
+	c := NewConsumer(saver, 1, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.Run(ctx)
+
+	err := c.Handler()(ctx, store.Message{Payload: "hi"})
+	assert.ErrorIs(t, err, wantErr)
+}
@@ -0,0 +1,49 @@
+// Package factory выбирает конкретную реализацию broker.Broker по её имени.
+package factory
+
+import (
+	"fmt"
+
+	"github.com/shulganew/alice-skill.git/internal/broker"
+	"github.com/shulganew/alice-skill.git/internal/broker/kafka"
+	"github.com/shulganew/alice-skill.git/internal/broker/memory"
+	"github.com/shulganew/alice-skill.git/internal/broker/nats"
+)
+
+// New создаёт Broker в соответствии с kind: "memory" (по умолчанию), "nats" или "kafka".
+// Выбор также управляется переменной окружения BROKER_KIND на уровне вызывающих cmd-пакетов.
+//
+// "memory" отдаёт буфер внутри текущего процесса — сообщения не переживают не то что
+// перезапуск, а даже отдельный вызов New в том же бинаре, и уж точно не видны другому
+// процессу. Он годится только там, где Publish и Subscribe живут в одном вызывающем коде
+// (юнит-тесты); cmd/skill и cmd/consumer — два отдельных процесса и не могут обмениваться
+// через него сообщениями. Вызывающий код (cmd/skill, cmd/consumer) обязан сам отказаться
+// от запуска с этим kind, см. вызов RequireDurable в обоих main.go.
+//
+// consumerName задаёт durable-консьюмера/consumer group для Subscribe у nats и kafka —
+// подписчики с разными именами независимо получают копию всех сообщений. Игнорируется для memory.
+func New(kind, consumerName string) (broker.Broker, error) {
+	switch kind {
+	case "", "memory":
+		return memory.NewBroker(1024), nil
+	case "nats":
+		return nats.NewBroker(consumerName)
+	case "kafka":
+		return kafka.NewBroker(consumerName)
+	default:
+		return nil, fmt.Errorf("unknown broker kind %q", kind)
+	}
+}
+
+// RequireDurable возвращает ошибку, если kind — это "memory": cmd/skill и cmd/consumer
+// работают как отдельные процессы и общаются друг с другом только через Broker, а memory
+// отдаёт приватный, никуда не переживающий канал внутри одного вызова New. Оба бинаря должны
+// вызвать эту проверку перед тем, как идти в New, иначе publish из одного процесса будет
+// бесследно теряться, а не просто не сохраняться.
+func RequireDurable(kind string) error {
+	if kind == "" || kind == "memory" {
+		return fmt.Errorf("broker kind %q only works within a single process/call and cannot bridge "+
+			"cmd/skill and cmd/consumer running as separate processes — set BROKER_KIND to nats or kafka", kind)
+	}
+	return nil
+}
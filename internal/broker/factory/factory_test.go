@@ -0,0 +1,14 @@
+package factory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireDurable(t *testing.T) {
+	assert.Error(t, RequireDurable(""))
+	assert.Error(t, RequireDurable("memory"))
+	assert.NoError(t, RequireDurable("nats"))
+	assert.NoError(t, RequireDurable("kafka"))
+}
@@ -0,0 +1,100 @@
+// Package kafka содержит реализацию broker.Broker поверх Kafka (segmentio/kafka-go).
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	kafkago "github.com/segmentio/kafka-go"
+
+	"github.com/shulganew/alice-skill.git/internal/broker"
+	"github.com/shulganew/alice-skill.git/internal/store"
+)
+
+const (
+	topicName      = "alice.messages"
+	defaultGroupID = "alice-skill-consumer"
+)
+
+// Broker — реализация broker.Broker на основе Kafka-топика.
+type Broker struct {
+	writer *kafkago.Writer
+	reader *kafkago.Reader
+}
+
+// NewBroker создаёт Broker, подключаясь к брокерам из переменной окружения
+// KAFKA_BROKERS (список адресов через запятую). groupID задаёт consumer group для
+// Subscribe; несколько Broker с разными группами независимо читают один и тот же топик.
+// Пустое значение использует группу по умолчанию.
+func NewBroker(groupID string) (*Broker, error) {
+	if groupID == "" {
+		groupID = defaultGroupID
+	}
+
+	brokersEnv := os.Getenv("KAFKA_BROKERS")
+	if brokersEnv == "" {
+		return nil, errors.New("KAFKA_BROKERS is not set")
+	}
+	brokers := strings.Split(brokersEnv, ",")
+
+	writer := &kafkago.Writer{
+		Addr:     kafkago.TCP(brokers...),
+		Topic:    topicName,
+		Balancer: &kafkago.LeastBytes{},
+	}
+
+	reader := kafkago.NewReader(kafkago.ReaderConfig{
+		Brokers: brokers,
+		Topic:   topicName,
+		GroupID: groupID,
+	})
+
+	return &Broker{writer: writer, reader: reader}, nil
+}
+
+// Publish публикует сообщение в топик Kafka.
+func (b *Broker) Publish(ctx context.Context, msg store.Message) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("cannot marshal message: %w", err)
+	}
+
+	if err := b.writer.WriteMessages(ctx, kafkago.Message{Value: payload}); err != nil {
+		return fmt.Errorf("cannot write message to kafka: %w", err)
+	}
+
+	return nil
+}
+
+// Subscribe читает сообщения из топика и вызывает handler для каждого, коммитя офсет
+// (явный ack) только после успешной обработки — обеспечивает семантику at-least-once.
+// Ошибка handler завершает Subscribe без коммита, так что сообщение будет прочитано
+// заново при следующей подписке группы.
+func (b *Broker) Subscribe(ctx context.Context, handler broker.Handler) error {
+	for {
+		m, err := b.reader.FetchMessage(ctx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return ctx.Err()
+			}
+			return fmt.Errorf("cannot fetch message from kafka: %w", err)
+		}
+
+		var msg store.Message
+		if err := json.Unmarshal(m.Value, &msg); err != nil {
+			continue
+		}
+
+		if err := handler(ctx, msg); err != nil {
+			return fmt.Errorf("cannot handle message at offset %d: %w", m.Offset, err)
+		}
+
+		if err := b.reader.CommitMessages(ctx, m); err != nil {
+			return fmt.Errorf("cannot commit message offset: %w", err)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Package memory содержит реализацию broker.Broker поверх канала в памяти процесса.
+// Используется как поведение по умолчанию и в тестах.
+package memory
+
+import (
+	"context"
+
+	"github.com/shulganew/alice-skill.git/internal/broker"
+	"github.com/shulganew/alice-skill.git/internal/store"
+)
+
+// Broker — реализация broker.Broker на основе буферизированного канала.
+// Сообщения не переживают перезапуск процесса.
+type Broker struct {
+	messages chan store.Message
+}
+
+// NewBroker создаёт Broker с буфером на capacity сообщений.
+func NewBroker(capacity int) *Broker {
+	return &Broker{messages: make(chan store.Message, capacity)}
+}
+
+// Publish кладёт сообщение в канал.
+func (b *Broker) Publish(ctx context.Context, msg store.Message) error {
+	select {
+	case b.messages <- msg:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Subscribe читает сообщения из канала и передаёт их в handler, пока не отменят ctx.
+// Если handler вернул ошибку, Subscribe завершается с ней, а сообщение в канал не
+// возвращается и повторно не доставляется.
+func (b *Broker) Subscribe(ctx context.Context, handler broker.Handler) error {
+	for {
+		select {
+		case msg := <-b.messages:
+			if err := handler(ctx, msg); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
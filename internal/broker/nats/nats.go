@@ -0,0 +1,114 @@
+// Package nats содержит реализацию broker.Broker поверх NATS JetStream.
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/shulganew/alice-skill.git/internal/broker"
+	"github.com/shulganew/alice-skill.git/internal/store"
+)
+
+const (
+	streamName          = "ALICE_MESSAGES"
+	subjectName         = "alice.messages"
+	defaultConsumerName = "alice-skill-consumer"
+)
+
+// Broker — реализация broker.Broker на основе durable-потока JetStream.
+type Broker struct {
+	js           jetstream.JetStream
+	stream       jetstream.Stream
+	consumerName string
+}
+
+// NewBroker подключается к NATS по адресу из переменной окружения NATS_URL
+// (по умолчанию nats.DefaultURL) и создаёт поток, если он ещё не существует.
+// consumerName задаёт имя durable-консьюмера для Subscribe; несколько Broker с разными
+// именами независимо читают один и тот же поток. Пустое значение использует имя по умолчанию.
+func NewBroker(consumerName string) (*Broker, error) {
+	if consumerName == "" {
+		consumerName = defaultConsumerName
+	}
+	url := os.Getenv("NATS_URL")
+	if url == "" {
+		url = nats.DefaultURL
+	}
+
+	nc, err := nats.Connect(url)
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to NATS: %w", err)
+	}
+
+	js, err := jetstream.New(nc)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create JetStream context: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     streamName,
+		Subjects: []string{subjectName},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot create JetStream stream: %w", err)
+	}
+
+	return &Broker{js: js, stream: stream, consumerName: consumerName}, nil
+}
+
+// Publish публикует сообщение в поток JetStream и ждёт подтверждения брокером.
+func (b *Broker) Publish(ctx context.Context, msg store.Message) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("cannot marshal message: %w", err)
+	}
+
+	if _, err := b.js.Publish(ctx, subjectName, payload); err != nil {
+		return fmt.Errorf("cannot publish message: %w", err)
+	}
+
+	return nil
+}
+
+// Subscribe создаёт durable-консьюмера и вызывает handler для каждого сообщения,
+// подтверждая (ack) его только после успешной обработки.
+func (b *Broker) Subscribe(ctx context.Context, handler broker.Handler) error {
+	cons, err := b.stream.CreateOrUpdateConsumer(ctx, jetstream.ConsumerConfig{
+		Durable:   b.consumerName,
+		AckPolicy: jetstream.AckExplicitPolicy,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot create JetStream consumer: %w", err)
+	}
+
+	consCtx, err := cons.Consume(func(m jetstream.Msg) {
+		var msg store.Message
+		if err := json.Unmarshal(m.Data(), &msg); err != nil {
+			m.Nak()
+			return
+		}
+
+		if err := handler(ctx, msg); err != nil {
+			m.Nak()
+			return
+		}
+
+		m.Ack()
+	})
+	if err != nil {
+		return fmt.Errorf("cannot start consuming: %w", err)
+	}
+	defer consCtx.Stop()
+
+	<-ctx.Done()
+	return ctx.Err()
+}
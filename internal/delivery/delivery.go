@@ -0,0 +1,205 @@
+// Package delivery рассылает сохранённые сообщения подписчикам получателя через
+// исходящие вебхуки (в стиле Mattermost outgoing webhooks).
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/shulganew/alice-skill.git/internal/logger"
+	"github.com/shulganew/alice-skill.git/internal/store"
+	"go.uber.org/zap"
+)
+
+// maxAttempts — максимальное число попыток доставки одного события прежде, чем
+// отметить его как окончательно неудавшееся (дальше его подхватит sweeper).
+const maxAttempts = 5
+
+// initialBackoff — задержка перед первой повторной попыткой, дальше растёт экспоненциально.
+// Var, а не const, чтобы тесты могли ускорить её и не ждать реальные секунды.
+var initialBackoff = 500 * time.Millisecond
+
+// job — одно событие доставки: сообщение, которое нужно отправить на URL подписки.
+type job struct {
+	sub store.Subscription
+	msg store.Message
+	// attempts — число уже совершённых попыток на момент постановки в очередь; 0 для
+	// новых событий из Notify, значение из deliveries для повторов, которые подхватил sweepOnce.
+	attempts int
+}
+
+// Dispatcher доставляет новые сообщения подписчикам получателя, ограничивая
+// параллелизм пулом воркеров и подписывая каждый запрос HMAC-секретом подписки.
+type Dispatcher struct {
+	store  store.Store
+	client *http.Client
+	jobs   chan job
+}
+
+// NewDispatcher создаёт Dispatcher с пулом из workers воркеров.
+func NewDispatcher(s store.Store, workers int) *Dispatcher {
+	d := &Dispatcher{
+		store:  s,
+		client: &http.Client{Timeout: 10 * time.Second},
+		jobs:   make(chan job, workers*4),
+	}
+
+	for i := 0; i < workers; i++ {
+		go d.worker()
+	}
+
+	return d
+}
+
+// Notify ставит сообщение в очередь на доставку всем подпискам его получателя. Notify
+// вызывается синхронно из cmd/consumer на горячем пути сохранения сообщений, поэтому не
+// блокируется на переполненной очереди — см. enqueue.
+func (d *Dispatcher) Notify(ctx context.Context, msg store.Message) error {
+	subs, err := d.store.ListSubscriptions(ctx, msg.Recepient)
+	if err != nil {
+		return fmt.Errorf("cannot list subscriptions: %w", err)
+	}
+
+	for _, sub := range subs {
+		d.enqueue(ctx, job{sub: sub, msg: msg})
+	}
+
+	return nil
+}
+
+// Sweep периодически перечитывает неудавшиеся доставки и повторяет их.
+func (d *Dispatcher) Sweep(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			d.sweepOnce(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) sweepOnce(ctx context.Context) {
+	deliveries, err := d.store.ListRetryableDeliveries(ctx)
+	if err != nil {
+		logger.Log.Debug("cannot list retryable deliveries", zap.Error(err))
+		return
+	}
+
+	for _, del := range deliveries {
+		d.enqueue(ctx, job{sub: del.Subscription, msg: del.Message, attempts: del.Attempts})
+	}
+}
+
+// enqueue кладёт job в очередь воркеров, не блокируясь: несколько медленных или
+// недоступных подписчиков не должны стопорить вызывающий код (Notify дёргается
+// синхронно из горячего пути сохранения сообщений в cmd/consumer). Если очередь
+// переполнена, попытка отправки не делается, но событие всё равно остаётся
+// retryable — записывается как failed в deliveries, чтобы его подхватил sweepOnce,
+// вместо того чтобы бесследно исчезнуть.
+func (d *Dispatcher) enqueue(ctx context.Context, j job) {
+	select {
+	case d.jobs <- j:
+	default:
+		logger.Log.Debug("delivery queue full, recording job as failed for later retry",
+			zap.Int64("subscription_id", j.sub.ID), zap.Int64("message_id", j.msg.ID))
+
+		record := store.Delivery{
+			Subscription: j.sub,
+			Message:      j.msg,
+			Status:       store.DeliveryStatusFailed,
+			Attempts:     j.attempts,
+			LastError:    "delivery queue full, dropped before attempting delivery",
+		}
+		if err := d.store.RecordDelivery(ctx, record); err != nil {
+			logger.Log.Debug("cannot record dropped delivery", zap.Error(err))
+		}
+	}
+}
+
+func (d *Dispatcher) worker() {
+	for j := range d.jobs {
+		d.deliver(context.Background(), j)
+	}
+}
+
+// deliver пытается доставить сообщение подписке с экспоненциальной задержкой между
+// попытками и записывает финальный статус в store.
+func (d *Dispatcher) deliver(ctx context.Context, j job) {
+	payload, err := json.Marshal(j.msg)
+	if err != nil {
+		logger.Log.Debug("cannot marshal message for delivery", zap.Error(err))
+		return
+	}
+
+	backoff := initialBackoff
+	var lastErr error
+	var attempts int
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		attempts = attempt
+		if lastErr = d.send(ctx, j.sub, payload); lastErr == nil {
+			break
+		}
+		logger.Log.Debug("delivery attempt failed",
+			zap.Int64("subscription_id", j.sub.ID), zap.Int("attempt", attempt), zap.Error(lastErr))
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+
+	status := store.DeliveryStatusDelivered
+	errMsg := ""
+	if lastErr != nil {
+		status = store.DeliveryStatusFailed
+		errMsg = lastErr.Error()
+	}
+
+	record := store.Delivery{
+		Subscription: j.sub,
+		Message:      j.msg,
+		Status:       status,
+		Attempts:     attempts,
+		LastError:    errMsg,
+	}
+	if err := d.store.RecordDelivery(ctx, record); err != nil {
+		logger.Log.Debug("cannot record delivery status", zap.Error(err))
+	}
+}
+
+// send выполняет один HTTP POST с телом события и подписью X-Signature.
+func (d *Dispatcher) send(ctx context.Context, sub store.Subscription, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("cannot build delivery request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Signature", "sha256="+sign(sub.Secret, payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot reach subscriber: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("subscriber responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// sign вычисляет HMAC-SHA256 тела запроса на секрете подписки.
+func sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,158 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shulganew/alice-skill.git/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStore — минимальная реализация store.Store для тестов Dispatcher: хранит
+// подписки в памяти и запоминает записанные store.Delivery.
+type fakeStore struct {
+	store.Store // встраивание паникует на неиспользуемых здесь методах
+
+	mu   sync.Mutex
+	subs []store.Subscription
+	recs []store.Delivery
+}
+
+func (f *fakeStore) ListSubscriptions(ctx context.Context, userID string) ([]store.Subscription, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]store.Subscription(nil), f.subs...), nil
+}
+
+func (f *fakeStore) RecordDelivery(ctx context.Context, d store.Delivery) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.recs = append(f.recs, d)
+	return nil
+}
+
+func (f *fakeStore) deliveries() []store.Delivery {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]store.Delivery(nil), f.recs...)
+}
+
+func TestSign(t *testing.T) {
+	// известный вектор HMAC-SHA256("secret", "payload")
+	got := sign("secret", []byte("payload"))
+	assert.Equal(t, "b82fcb791acec57859b989b430a826488ce2e479fdf92326bd0a2e8375a42ba4", got)
+}
+
+func TestDispatcher_Notify_DeliversOnFirstAttempt(t *testing.T) {
+	var gotSignature string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSignature = r.Header.Get("X-Signature")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	fs := &fakeStore{subs: []store.Subscription{{ID: 1, UserID: "ivan", URL: srv.URL, Secret: "secret"}}}
+	d := NewDispatcher(fs, 1)
+
+	err := d.Notify(context.Background(), store.Message{ID: 42, Recepient: "ivan", Payload: "hi"})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return len(fs.deliveries()) == 1 }, time.Second, 5*time.Millisecond)
+
+	recs := fs.deliveries()
+	assert.Equal(t, store.DeliveryStatusDelivered, recs[0].Status)
+	assert.Equal(t, 1, recs[0].Attempts)
+	assert.Equal(t, "", recs[0].LastError)
+	assert.NotEmpty(t, gotSignature)
+}
+
+func TestDispatcher_Notify_RetriesThenDelivers(t *testing.T) {
+	prevBackoff := initialBackoff
+	initialBackoff = time.Millisecond
+	defer func() { initialBackoff = prevBackoff }()
+
+	var mu sync.Mutex
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		n := calls
+		mu.Unlock()
+		if n == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	fs := &fakeStore{subs: []store.Subscription{{ID: 1, UserID: "ivan", URL: srv.URL, Secret: "secret"}}}
+	d := NewDispatcher(fs, 1)
+
+	err := d.Notify(context.Background(), store.Message{ID: 42, Recepient: "ivan", Payload: "hi"})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return len(fs.deliveries()) == 1 }, time.Second, 5*time.Millisecond)
+
+	recs := fs.deliveries()
+	assert.Equal(t, store.DeliveryStatusDelivered, recs[0].Status)
+	assert.Equal(t, 2, recs[0].Attempts)
+}
+
+func TestDispatcher_Notify_FailsAfterMaxAttempts(t *testing.T) {
+	prevBackoff := initialBackoff
+	initialBackoff = time.Millisecond
+	defer func() { initialBackoff = prevBackoff }()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	fs := &fakeStore{subs: []store.Subscription{{ID: 1, UserID: "ivan", URL: srv.URL, Secret: "secret"}}}
+	d := NewDispatcher(fs, 1)
+
+	err := d.Notify(context.Background(), store.Message{ID: 42, Recepient: "ivan", Payload: "hi"})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return len(fs.deliveries()) == 1 }, time.Second, 5*time.Millisecond)
+
+	recs := fs.deliveries()
+	assert.Equal(t, store.DeliveryStatusFailed, recs[0].Status)
+	assert.Equal(t, maxAttempts, recs[0].Attempts)
+	assert.NotEmpty(t, recs[0].LastError)
+}
+
+func TestDispatcher_Notify_QueueFullRecordsFailedDelivery(t *testing.T) {
+	block := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	defer close(block)
+
+	fs := &fakeStore{subs: []store.Subscription{{ID: 1, UserID: "ivan", URL: srv.URL, Secret: "secret"}}}
+	d := NewDispatcher(fs, 1)
+
+	// буфер — workers*4 = 4; шлём с запасом, чтобы переполнение случилось независимо от
+	// того, успеет ли единственный воркер забрать первое событие до конца цикла
+	msg := store.Message{ID: 42, Recepient: "ivan", Payload: "hi"}
+	for i := 0; i < 10; i++ {
+		require.NoError(t, d.Notify(context.Background(), msg))
+	}
+
+	require.Eventually(t, func() bool {
+		for _, rec := range fs.deliveries() {
+			if rec.LastError == "delivery queue full, dropped before attempting delivery" {
+				return true
+			}
+		}
+		return false
+	}, time.Second, 5*time.Millisecond)
+}
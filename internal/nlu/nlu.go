@@ -0,0 +1,51 @@
+// Package nlu абстрагирует распознавание намерения пользователя (интента) и его
+// слотов из assistant.Utterance, заменяя разбор команд по префиксу строки.
+package nlu
+
+import "github.com/shulganew/alice-skill.git/internal/assistant"
+
+// Именованные интенты, которые понимает навык.
+const (
+	IntentSend      = "send"
+	IntentRead      = "read"
+	IntentRegister  = "register"
+	IntentSubscribe = "subscribe"
+)
+
+// Intent — распознанное намерение пользователя вместе с извлечёнными слотами.
+// Name пуст, если ни одна реализация Recognizer не смогла разобрать Utterance.
+type Intent struct {
+	Name  string
+	Slots map[string]string
+}
+
+// Recognizer разбирает Utterance в Intent. Возвращает Intent{} с пустым Name
+// (не ошибку), если намерение не распознано — это штатный случай.
+type Recognizer interface {
+	Recognize(u assistant.Utterance) (Intent, error)
+}
+
+// Fallback возвращает Recognizer, который сначала спрашивает primary и, если тот
+// не распознал интент (Name == ""), пробует secondary. Используется, чтобы
+// предпочесть встроенную NLU платформы (см. internal/nlu/yandex) и откатиться
+// на собственный разбор регэкспами (см. internal/nlu/rulebased), когда платформа
+// его не прислала или не справилась.
+func Fallback(primary, secondary Recognizer) Recognizer {
+	return fallbackRecognizer{primary: primary, secondary: secondary}
+}
+
+type fallbackRecognizer struct {
+	primary   Recognizer
+	secondary Recognizer
+}
+
+func (f fallbackRecognizer) Recognize(u assistant.Utterance) (Intent, error) {
+	intent, err := f.primary.Recognize(u)
+	if err != nil {
+		return Intent{}, err
+	}
+	if intent.Name != "" {
+		return intent, nil
+	}
+	return f.secondary.Recognize(u)
+}
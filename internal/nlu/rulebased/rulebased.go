@@ -0,0 +1,124 @@
+// Package rulebased реализует nlu.Recognizer без обращения к платформе: чистыми
+// регулярными выражениями поверх текста команды.
+package rulebased
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/shulganew/alice-skill.git/internal/assistant"
+	"github.com/shulganew/alice-skill.git/internal/nlu"
+)
+
+// verbEndings перечисляет частые окончания повелительного наклонения и инфинитива
+// русских глаголов. stem отбрасывает самое длинное подходящее из них, чтобы
+// "Отправь", "Отправить" и "Отправьте" сводились к одному и тому же корню.
+var verbEndings = []string{"ите", "йте", "ь", "и"}
+
+// stem грубо приводит слово к корню глагола, отбрасывая окончание из verbEndings.
+// Это не полноценный морфологический разбор, а дешёвая нормализация: даже когда
+// окончание не распознано, совпадение intentRule.roots по префиксу обычно всё
+// равно срабатывает, потому что корень русского глагола — префикс его форм.
+func stem(word string) string {
+	lower := strings.ToLower(word)
+	for _, ending := range verbEndings {
+		if strings.HasSuffix(lower, ending) && len(lower) > len(ending)+2 {
+			return strings.TrimSuffix(lower, ending)
+		}
+	}
+	return lower
+}
+
+// intentRule — правило распознавания одного интента: roots — застемленные корни
+// первого слова команды, на которые реагирует правило; slots — регэксп с
+// именованными группами для извлечения слотов из остатка фразы.
+type intentRule struct {
+	intent string
+	roots  []string
+	slots  *regexp.Regexp
+}
+
+var rules = []intentRule{
+	{
+		intent: nlu.IntentSend,
+		roots:  []string{"отправ", "напиш"},
+		slots:  regexp.MustCompile(`(?P<user>\S+)\s+(?P<text>.+)`),
+	},
+	{
+		intent: nlu.IntentRead,
+		roots:  []string{"прочит"},
+		slots:  regexp.MustCompile(`(?P<index>\d+)`),
+	},
+	{
+		intent: nlu.IntentRegister,
+		roots:  []string{"зарегистр"},
+		slots:  regexp.MustCompile(`(?P<user>\S+)`),
+	},
+	{
+		intent: nlu.IntentSubscribe,
+		// у глагола "подписать" чередуется согласная корня (с/ш) между инфинитивом
+		// и повелительным наклонением, поэтому нужны оба варианта корня.
+		roots: []string{"подпиш", "подпис"},
+		slots: regexp.MustCompile(`(?P<url>\S+)\s+(?P<secret>\S+)`),
+	},
+}
+
+// Recognizer — реализация nlu.Recognizer на основе compiled-regexp + стеммера.
+type Recognizer struct{}
+
+// New создаёт рулбейзд Recognizer.
+func New() Recognizer {
+	return Recognizer{}
+}
+
+// Recognize определяет интент по первому слову фразы (после стемминга) и
+// заполняет слоты из остатка фразы по регэкспу правила. Возвращает Intent{}
+// с пустым Name, если ни одно правило не подошло.
+func (Recognizer) Recognize(u assistant.Utterance) (nlu.Intent, error) {
+	text := strings.TrimSpace(u.Text)
+	if text == "" {
+		return nlu.Intent{}, nil
+	}
+
+	parts := strings.SplitN(text, " ", 2)
+	root := stem(parts[0])
+	rest := ""
+	if len(parts) > 1 {
+		rest = parts[1]
+	}
+
+	for _, r := range rules {
+		if !hasAnyPrefix(root, r.roots) {
+			continue
+		}
+		return nlu.Intent{Name: r.intent, Slots: extractSlots(r.slots, rest)}, nil
+	}
+
+	return nlu.Intent{}, nil
+}
+
+// extractSlots применяет regexp с именованными группами к rest и возвращает
+// найденные слоты. Именованные группы, которые не совпали, в результат не попадают.
+func extractSlots(pattern *regexp.Regexp, rest string) map[string]string {
+	match := pattern.FindStringSubmatch(rest)
+	if match == nil {
+		return map[string]string{}
+	}
+
+	slots := make(map[string]string, len(match))
+	for i, name := range pattern.SubexpNames() {
+		if name != "" && match[i] != "" {
+			slots[name] = match[i]
+		}
+	}
+	return slots
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
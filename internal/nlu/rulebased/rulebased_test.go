@@ -0,0 +1,74 @@
+package rulebased
+
+import (
+	"testing"
+
+	"github.com/shulganew/alice-skill.git/internal/assistant"
+	"github.com/shulganew/alice-skill.git/internal/nlu"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRecognizer_Recognize — golden-набор фраз для каждого интента, который
+// фиксирует конкретные формулировки, устойчивые к морфологии глагола, и
+// страхует от регрессий при правке правил или стеммера.
+func TestRecognizer_Recognize(t *testing.T) {
+	testCases := []struct {
+		name string
+		text string
+		want nlu.Intent
+	}{
+		// intent: send
+		{"send_imperative_short", "Отправь ivan привет", nlu.Intent{Name: nlu.IntentSend, Slots: map[string]string{"user": "ivan", "text": "привет"}}},
+		{"send_infinitive", "Отправить ivan как дела", nlu.Intent{Name: nlu.IntentSend, Slots: map[string]string{"user": "ivan", "text": "как дела"}}},
+		{"send_formal_plural", "Отправьте ivan добрый день", nlu.Intent{Name: nlu.IntentSend, Slots: map[string]string{"user": "ivan", "text": "добрый день"}}},
+		{"send_napishi", "Напиши petr давай встретимся", nlu.Intent{Name: nlu.IntentSend, Slots: map[string]string{"user": "petr", "text": "давай встретимся"}}},
+		{"send_napishite", "Напишите petr увидимся завтра", nlu.Intent{Name: nlu.IntentSend, Slots: map[string]string{"user": "petr", "text": "увидимся завтра"}}},
+		{"send_uppercase_verb", "ОТПРАВЬ ivan привет", nlu.Intent{Name: nlu.IntentSend, Slots: map[string]string{"user": "ivan", "text": "привет"}}},
+		{"send_multi_word_message", "Отправь maria встретимся в семь вечера", nlu.Intent{Name: nlu.IntentSend, Slots: map[string]string{"user": "maria", "text": "встретимся в семь вечера"}}},
+		{"send_short_username", "Отправь a привет", nlu.Intent{Name: nlu.IntentSend, Slots: map[string]string{"user": "a", "text": "привет"}}},
+		{"send_numeric_username", "Отправь user42 тест", nlu.Intent{Name: nlu.IntentSend, Slots: map[string]string{"user": "user42", "text": "тест"}}},
+		{"send_message_with_punctuation", "Отправь ivan привет, как дела?", nlu.Intent{Name: nlu.IntentSend, Slots: map[string]string{"user": "ivan", "text": "привет, как дела?"}}},
+
+		// intent: read
+		{"read_bare", "Прочитай", nlu.Intent{Name: nlu.IntentRead, Slots: map[string]string{}}},
+		{"read_infinitive", "Прочитать", nlu.Intent{Name: nlu.IntentRead, Slots: map[string]string{}}},
+		{"read_formal_plural", "Прочитайте", nlu.Intent{Name: nlu.IntentRead, Slots: map[string]string{}}},
+		{"read_with_noun", "Прочитай сообщение", nlu.Intent{Name: nlu.IntentRead, Slots: map[string]string{}}},
+		{"read_with_index", "Прочитай сообщение 2", nlu.Intent{Name: nlu.IntentRead, Slots: map[string]string{"index": "2"}}},
+		{"read_with_index_no_noun", "Прочитай 1", nlu.Intent{Name: nlu.IntentRead, Slots: map[string]string{"index": "1"}}},
+		{"read_uppercase_verb", "ПРОЧИТАЙ 3", nlu.Intent{Name: nlu.IntentRead, Slots: map[string]string{"index": "3"}}},
+		{"read_large_index", "Прочитай сообщение номер 12", nlu.Intent{Name: nlu.IntentRead, Slots: map[string]string{"index": "12"}}},
+
+		// intent: register
+		{"register_imperative", "Зарегистрируй ivan", nlu.Intent{Name: nlu.IntentRegister, Slots: map[string]string{"user": "ivan"}}},
+		{"register_infinitive", "Зарегистрировать ivan", nlu.Intent{Name: nlu.IntentRegister, Slots: map[string]string{"user": "ivan"}}},
+		{"register_formal_plural", "Зарегистрируйте ivan", nlu.Intent{Name: nlu.IntentRegister, Slots: map[string]string{"user": "ivan"}}},
+		{"register_numeric_username", "Зарегистрируй ivan2024", nlu.Intent{Name: nlu.IntentRegister, Slots: map[string]string{"user": "ivan2024"}}},
+		{"register_uppercase_verb", "ЗАРЕГИСТРИРУЙ petr", nlu.Intent{Name: nlu.IntentRegister, Slots: map[string]string{"user": "petr"}}},
+		{"register_short_username", "Зарегистрируй a", nlu.Intent{Name: nlu.IntentRegister, Slots: map[string]string{"user": "a"}}},
+
+		// intent: subscribe
+		{"subscribe_imperative", "Подпиши https://example.com/hook secret1", nlu.Intent{Name: nlu.IntentSubscribe, Slots: map[string]string{"url": "https://example.com/hook", "secret": "secret1"}}},
+		{"subscribe_infinitive", "Подписать https://example.com/hook secret1", nlu.Intent{Name: nlu.IntentSubscribe, Slots: map[string]string{"url": "https://example.com/hook", "secret": "secret1"}}},
+		{"subscribe_formal_plural", "Подпишите https://example.com/hook secret1", nlu.Intent{Name: nlu.IntentSubscribe, Slots: map[string]string{"url": "https://example.com/hook", "secret": "secret1"}}},
+		{"subscribe_another_url", "Подпиши https://hooks.example.org/alice topsecret", nlu.Intent{Name: nlu.IntentSubscribe, Slots: map[string]string{"url": "https://hooks.example.org/alice", "secret": "topsecret"}}},
+		{"subscribe_uppercase_verb", "ПОДПИШИ https://example.com/hook secret1", nlu.Intent{Name: nlu.IntentSubscribe, Slots: map[string]string{"url": "https://example.com/hook", "secret": "secret1"}}},
+		{"subscribe_numeric_secret", "Подпиши https://example.com/hook 12345", nlu.Intent{Name: nlu.IntentSubscribe, Slots: map[string]string{"url": "https://example.com/hook", "secret": "12345"}}},
+
+		// неизвестные и пустые фразы — интент не распознан
+		{"unknown_phrase", "Какая погода сегодня", nlu.Intent{}},
+		{"empty_phrase", "", nlu.Intent{}},
+		{"whitespace_only", "   ", nlu.Intent{}},
+		{"single_unrelated_word", "Привет", nlu.Intent{}},
+	}
+
+	r := New()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := r.Recognize(assistant.Utterance{Text: tc.text})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Package yandex реализует nlu.Recognizer поверх встроенной NLU Яндекс.Диалогов:
+// вместо разбора текста он берёт уже распознанный Яндексом интент и слоты из
+// assistant.Utterance.Nlu (см. https://yandex.ru/dev/dialogs/alice/doc/nlu.html).
+// Интенты для этого нужно завести в консоли навыка под именами из intentNames.
+package yandex
+
+import (
+	"github.com/shulganew/alice-skill.git/internal/assistant"
+	"github.com/shulganew/alice-skill.git/internal/nlu"
+)
+
+// intentNames сопоставляет имена интентов, заведённые в консоли Яндекс.Диалогов,
+// каноническим именам интентов навыка.
+var intentNames = map[string]string{
+	"send_message":  nlu.IntentSend,
+	"read_message":  nlu.IntentRead,
+	"register_user": nlu.IntentRegister,
+	"subscribe":     nlu.IntentSubscribe,
+}
+
+// Recognizer — реализация nlu.Recognizer поверх встроенной NLU Яндекс.Диалогов.
+type Recognizer struct{}
+
+// New создаёт Recognizer для встроенной NLU Яндекс.Диалогов.
+func New() Recognizer {
+	return Recognizer{}
+}
+
+// Recognize возвращает Intent{} с пустым Name, если Алиса не прислала NLU-разметку
+// (u.Nlu == nil, например для других платформ) или не распознала ни один из
+// известных intentNames — тогда стоит откатиться на nlu.rulebased.
+func (Recognizer) Recognize(u assistant.Utterance) (nlu.Intent, error) {
+	if u.Nlu == nil {
+		return nlu.Intent{}, nil
+	}
+
+	for platformName, canonical := range intentNames {
+		intent, ok := u.Nlu.Intents[platformName]
+		if !ok {
+			continue
+		}
+
+		slots := make(map[string]string, len(intent.Slots))
+		for name, slot := range intent.Slots {
+			slots[name] = slot.Value
+		}
+
+		return nlu.Intent{Name: canonical, Slots: slots}, nil
+	}
+
+	return nlu.Intent{}, nil
+}
@@ -0,0 +1,60 @@
+package yandex
+
+import (
+	"testing"
+
+	"github.com/shulganew/alice-skill.git/internal/assistant"
+	"github.com/shulganew/alice-skill.git/internal/nlu"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecognizer_Recognize(t *testing.T) {
+	testCases := []struct {
+		name string
+		u    assistant.Utterance
+		want nlu.Intent
+	}{
+		{
+			name: "no_nlu_hint",
+			u:    assistant.Utterance{Text: "Отправь ivan привет"},
+			want: nlu.Intent{},
+		},
+		{
+			name: "known_intent_with_slots",
+			u: assistant.Utterance{
+				Nlu: &assistant.NluHint{
+					Intents: map[string]assistant.NluIntent{
+						"send_message": {
+							Slots: map[string]assistant.NluSlot{
+								"user": {Type: "YANDEX.STRING", Value: "ivan"},
+								"text": {Type: "YANDEX.STRING", Value: "привет"},
+							},
+						},
+					},
+				},
+			},
+			want: nlu.Intent{Name: nlu.IntentSend, Slots: map[string]string{"user": "ivan", "text": "привет"}},
+		},
+		{
+			name: "unrecognized_intent_name",
+			u: assistant.Utterance{
+				Nlu: &assistant.NluHint{
+					Intents: map[string]assistant.NluIntent{
+						"order_pizza": {Slots: map[string]assistant.NluSlot{}},
+					},
+				},
+			},
+			want: nlu.Intent{},
+		},
+	}
+
+	r := New()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := r.Recognize(tc.u)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
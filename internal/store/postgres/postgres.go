@@ -0,0 +1,305 @@
+// Package postgres реализует store.Store поверх PostgreSQL.
+package postgres
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"github.com/shulganew/alice-skill.git/internal/store"
+)
+
+// migrations содержит миграции схемы, встроенные в бинарь.
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Store — реализация store.Store поверх *sql.DB с драйвером pgx.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore открывает соединение с PostgreSQL по dsn, накатывает миграции и возвращает готовое хранилище.
+func NewStore(ctx context.Context, dsn string) (*Store, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open database connection: %w", err)
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("cannot ping database: %w", err)
+	}
+
+	if err := runMigrations(db); err != nil {
+		return nil, fmt.Errorf("cannot apply migrations: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// runMigrations идемпотентно накатывает схему из встроенной директории migrations.
+func runMigrations(db *sql.DB) error {
+	source, err := iofs.New(migrations, "migrations")
+	if err != nil {
+		return fmt.Errorf("cannot read embedded migrations: %w", err)
+	}
+
+	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	if err != nil {
+		return fmt.Errorf("cannot create migrate driver: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, "pgx", driver)
+	if err != nil {
+		return fmt.Errorf("cannot create migrate instance: %w", err)
+	}
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+
+	return nil
+}
+
+// FindRecipient возвращает внутренний идентификатор пользователя по понятному человеку имени.
+func (s *Store) FindRecipient(ctx context.Context, username string) (string, error) {
+	var userID string
+	row := s.db.QueryRowContext(ctx, `SELECT user_id FROM users WHERE username = $1`, username)
+	if err := row.Scan(&userID); err != nil {
+		return "", fmt.Errorf("cannot find recipient by username: %w", err)
+	}
+	return userID, nil
+}
+
+// ListMessages возвращает список всех сообщений для определённого получателя.
+func (s *Store) ListMessages(ctx context.Context, userID string) ([]store.Message, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, sender, recipient, sent_at, payload
+		FROM messages
+		WHERE recipient = $1
+		ORDER BY sent_at`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []store.Message
+	for rows.Next() {
+		var m store.Message
+		if err := rows.Scan(&m.ID, &m.Sender, &m.Recepient, &m.Time, &m.Payload); err != nil {
+			return nil, fmt.Errorf("cannot scan message row: %w", err)
+		}
+		messages = append(messages, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("cannot list messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// GetMessage возвращает сообщение с определённым ID.
+func (s *Store) GetMessage(ctx context.Context, id int64) (*store.Message, error) {
+	var m store.Message
+	row := s.db.QueryRowContext(ctx, `
+		SELECT id, sender, recipient, sent_at, payload
+		FROM messages
+		WHERE id = $1`, id)
+	if err := row.Scan(&m.ID, &m.Sender, &m.Recepient, &m.Time, &m.Payload); err != nil {
+		return nil, fmt.Errorf("cannot get message: %w", err)
+	}
+	return &m, nil
+}
+
+// SaveMessages сохраняет новые сообщения одной транзакцией и возвращает их же с
+// присвоенными id, чтобы вызывающий код (уведомления подписчикам, push по WebSocket)
+// мог адресовать сообщения их настоящим id, а не нулём.
+func (s *Store) SaveMessages(ctx context.Context, messages ...store.Message) ([]store.Message, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO messages (sender, recipient, sent_at, payload)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`)
+	if err != nil {
+		return nil, fmt.Errorf("cannot prepare insert statement: %w", err)
+	}
+	defer stmt.Close()
+
+	saved := make([]store.Message, len(messages))
+	for i, m := range messages {
+		if err := stmt.QueryRowContext(ctx, m.Sender, m.Recepient, m.Time, m.Payload).Scan(&m.ID); err != nil {
+			return nil, fmt.Errorf("cannot save message: %w", err)
+		}
+		saved[i] = m
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("cannot commit transaction: %w", err)
+	}
+
+	return saved, nil
+}
+
+// RegisterUser регистрирует нового пользователя, выдавая ему токен для WebSocket-подключений.
+// При конфликте имени пользователя возвращает store.ErrConflict.
+func (s *Store) RegisterUser(ctx context.Context, userID, username string) (string, error) {
+	token, err := generateToken()
+	if err != nil {
+		return "", fmt.Errorf("cannot generate user token: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO users (user_id, username, token)
+		VALUES ($1, $2, $3)`, userID, username, token)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return "", store.ErrConflict
+		}
+		return "", fmt.Errorf("cannot register user: %w", err)
+	}
+
+	return token, nil
+}
+
+// VerifyUserToken проверяет, что token был выдан username при регистрации, и возвращает
+// внутренний идентификатор этого пользователя.
+func (s *Store) VerifyUserToken(ctx context.Context, username, token string) (string, bool, error) {
+	var userID string
+	row := s.db.QueryRowContext(ctx, `
+		SELECT user_id FROM users WHERE username = $1 AND token = $2`, username, token)
+	if err := row.Scan(&userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("cannot verify user token: %w", err)
+	}
+
+	return userID, true, nil
+}
+
+// generateToken выдаёт случайный токен для WebSocket-подключений пользователя.
+func generateToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// AddSubscription регистрирует новую подписку пользователя на доставку его новых сообщений по HTTP.
+func (s *Store) AddSubscription(ctx context.Context, userID, url, secret string) (store.Subscription, error) {
+	sub := store.Subscription{UserID: userID, URL: url, Secret: secret}
+
+	row := s.db.QueryRowContext(ctx, `
+		INSERT INTO subscriptions (user_id, url, secret)
+		VALUES ($1, $2, $3)
+		RETURNING id`, userID, url, secret)
+	if err := row.Scan(&sub.ID); err != nil {
+		return store.Subscription{}, fmt.Errorf("cannot add subscription: %w", err)
+	}
+
+	return sub, nil
+}
+
+// ListSubscriptions возвращает все подписки пользователя.
+func (s *Store) ListSubscriptions(ctx context.Context, userID string) ([]store.Subscription, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, user_id, url, secret
+		FROM subscriptions
+		WHERE user_id = $1
+		ORDER BY id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []store.Subscription
+	for rows.Next() {
+		var sub store.Subscription
+		if err := rows.Scan(&sub.ID, &sub.UserID, &sub.URL, &sub.Secret); err != nil {
+			return nil, fmt.Errorf("cannot scan subscription row: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("cannot list subscriptions: %w", err)
+	}
+
+	return subs, nil
+}
+
+// DeleteSubscription удаляет подписку по её идентификатору.
+func (s *Store) DeleteSubscription(ctx context.Context, id int64) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM subscriptions WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("cannot delete subscription: %w", err)
+	}
+	return nil
+}
+
+// RecordDelivery сохраняет финальный статус одной попытки доставки сообщения подписчику,
+// обновляя запись о предыдущей попытке доставки того же сообщения той же подписке, если она есть.
+func (s *Store) RecordDelivery(ctx context.Context, d store.Delivery) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO deliveries (subscription_id, message_id, status, attempts, last_error)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (subscription_id, message_id)
+		DO UPDATE SET status = EXCLUDED.status, attempts = EXCLUDED.attempts, last_error = EXCLUDED.last_error`,
+		d.Subscription.ID, d.Message.ID, d.Status, d.Attempts, d.LastError)
+	if err != nil {
+		return fmt.Errorf("cannot record delivery: %w", err)
+	}
+
+	return nil
+}
+
+// ListRetryableDeliveries возвращает недоставленные события вместе с их подпиской и сообщением.
+func (s *Store) ListRetryableDeliveries(ctx context.Context) ([]store.Delivery, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			d.id, d.status, d.attempts, d.last_error,
+			s.id, s.user_id, s.url, s.secret,
+			m.id, m.sender, m.recipient, m.sent_at, m.payload
+		FROM deliveries d
+		JOIN subscriptions s ON s.id = d.subscription_id
+		JOIN messages m ON m.id = d.message_id
+		WHERE d.status = $1`, store.DeliveryStatusFailed)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list retryable deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []store.Delivery
+	for rows.Next() {
+		var d store.Delivery
+		if err := rows.Scan(
+			&d.ID, &d.Status, &d.Attempts, &d.LastError,
+			&d.Subscription.ID, &d.Subscription.UserID, &d.Subscription.URL, &d.Subscription.Secret,
+			&d.Message.ID, &d.Message.Sender, &d.Message.Recepient, &d.Message.Time, &d.Message.Payload,
+		); err != nil {
+			return nil, fmt.Errorf("cannot scan delivery row: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("cannot list retryable deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
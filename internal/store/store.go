@@ -17,10 +17,25 @@ type Store interface {
 	ListMessages(ctx context.Context, userID string) ([]Message, error)
 	// GetMessage возвращает сообщение с определённым ID.
 	GetMessage(ctx context.Context, id int64) (*Message, error)
-	// SaveMessage сохраняет новое сообщения.
-	SaveMessages(ctx context.Context, messages ...Message) error
-	// RegisterUser регистрирует нового пользователя
-	RegisterUser(ctx context.Context, userID, username string) error
+	// SaveMessages сохраняет новые сообщения и возвращает их же с заполненным ID,
+	// присвоенным хранилищем, — вызывающий код (уведомления, push) должен адресовать
+	// сообщения реальным ID, а не нулём.
+	SaveMessages(ctx context.Context, messages ...Message) ([]Message, error)
+	// RegisterUser регистрирует нового пользователя и возвращает токен для WebSocket-подключений (см. wsserver).
+	RegisterUser(ctx context.Context, userID, username string) (token string, err error)
+	// VerifyUserToken проверяет, что token был выдан username при регистрации, и возвращает
+	// внутренний идентификатор этого пользователя.
+	VerifyUserToken(ctx context.Context, username, token string) (userID string, ok bool, err error)
+	// AddSubscription регистрирует новую подписку пользователя на доставку его новых сообщений по HTTP.
+	AddSubscription(ctx context.Context, userID, url, secret string) (Subscription, error)
+	// ListSubscriptions возвращает все подписки пользователя.
+	ListSubscriptions(ctx context.Context, userID string) ([]Subscription, error)
+	// DeleteSubscription удаляет подписку по её идентификатору.
+	DeleteSubscription(ctx context.Context, id int64) error
+	// RecordDelivery сохраняет финальный статус одной попытки доставки сообщения подписчику.
+	RecordDelivery(ctx context.Context, d Delivery) error
+	// ListRetryableDeliveries возвращает недоставленные события, которые нужно повторить.
+	ListRetryableDeliveries(ctx context.Context) ([]Delivery, error)
 }
 
 // Message описывает объект сообщения.
@@ -31,3 +46,33 @@ type Message struct {
 	Time      time.Time // время отправления
 	Payload   string    // текст сообщения
 }
+
+// Subscription описывает подписку пользователя на доставку его новых сообщений
+// сторонним HTTP-сервисом (исходящий вебхук).
+type Subscription struct {
+	ID     int64  // внутренний идентификатор подписки
+	UserID string // пользователь, на сообщения которого оформлена подписка
+	URL    string // адрес, на который доставляются события
+	Secret string `json:"-"` // секрет для подписи X-Signature; не должен покидать процесс через JSON
+}
+
+// DeliveryStatus описывает финальный статус попытки доставки события подписчику.
+type DeliveryStatus string
+
+const (
+	// DeliveryStatusDelivered означает, что подписчик успешно принял событие.
+	DeliveryStatusDelivered DeliveryStatus = "delivered"
+	// DeliveryStatusFailed означает, что все попытки доставки исчерпаны безуспешно либо
+	// событие не попало в переполненную очередь доставки и ни одной попытки не было.
+	DeliveryStatusFailed DeliveryStatus = "failed"
+)
+
+// Delivery описывает результат доставки одного сообщения одной подписке.
+type Delivery struct {
+	ID           int64          // внутренний идентификатор события доставки
+	Subscription Subscription   // подписка, которой доставлялось сообщение
+	Message      Message        // доставляемое сообщение
+	Status       DeliveryStatus // финальный статус попытки
+	Attempts     int            // число совершённых попыток
+	LastError    string         // текст последней ошибки, если доставка не удалась
+}
@@ -0,0 +1,180 @@
+// Package wsserver раздаёт новые сообщения зарегистрированным пользователям в реальном
+// времени через WebSocket, так что лёгкий клиент может получать их, не опрашивая API.
+package wsserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+
+	"github.com/shulganew/alice-skill.git/internal/logger"
+	"github.com/shulganew/alice-skill.git/internal/store"
+	"go.uber.org/zap"
+)
+
+const (
+	// pingInterval — как часто посылать keepalive-пинги живым подключениям.
+	pingInterval = 30 * time.Second
+	// sendBuffer — размер буфера исходящих сообщений на одно подключение;
+	// при переполнении клиент считается медленным и отключается.
+	sendBuffer = 16
+)
+
+// Authenticator проверяет токен WebSocket-подключения, выданный пользователю при регистрации,
+// и возвращает внутренний идентификатор пользователя (тот же, которым Broadcast адресует
+// сообщения, см. store.Message.Recepient).
+type Authenticator func(ctx context.Context, username, token string) (userID string, ok bool, err error)
+
+// conn — одно живое WebSocket-подключение пользователя.
+type conn struct {
+	ws   *websocket.Conn
+	send chan []byte
+	once sync.Once
+}
+
+// drop закрывает подключение медленного клиента, чьё буфер исходящих сообщений переполнился.
+func (c *conn) drop() {
+	c.once.Do(func() {
+		close(c.send)
+		c.ws.Close(websocket.StatusPolicyViolation, "slow consumer")
+	})
+}
+
+// Hub держит живые подключения, сгруппированные по внутреннему идентификатору
+// пользователя-получателя (store.Message.Recepient), и рассылает им новые сообщения.
+type Hub struct {
+	mu    sync.Mutex
+	conns map[string][]*conn
+}
+
+// NewHub создаёт пустой Hub.
+func NewHub() *Hub {
+	return &Hub{conns: make(map[string][]*conn)}
+}
+
+// Broadcast рассылает сообщение всем живым подключениям получателя, адресуя их тем же
+// userID, что и msg.Recepient. Подключения с переполненным буфером отключаются, а не
+// блокируют рассылку остальным.
+func (h *Hub) Broadcast(recipient string, msg store.Message) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		logger.Log.Debug("cannot marshal message for broadcast", zap.Error(err))
+		return
+	}
+
+	h.mu.Lock()
+	conns := append([]*conn(nil), h.conns[recipient]...)
+	h.mu.Unlock()
+
+	for _, c := range conns {
+		select {
+		case c.send <- payload:
+		default:
+			c.drop()
+		}
+	}
+}
+
+func (h *Hub) add(userID string, c *conn) {
+	h.mu.Lock()
+	h.conns[userID] = append(h.conns[userID], c)
+	h.mu.Unlock()
+}
+
+func (h *Hub) remove(userID string, c *conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cs := h.conns[userID]
+	for i, existing := range cs {
+		if existing == c {
+			h.conns[userID] = append(cs[:i], cs[i+1:]...)
+			break
+		}
+	}
+	if len(h.conns[userID]) == 0 {
+		delete(h.conns, userID)
+	}
+}
+
+// Handler обслуживает GET /ws?user=<username>&token=<t>: проверяет токен через auth,
+// получая заодно внутренний userID, поднимает соединение до WebSocket и подписывает
+// его на рассылки Hub для этого userID — тем же идентификатором, которым адресованы
+// сообщения в store.Message.Recepient.
+func Handler(hub *Hub, auth Authenticator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		username := r.URL.Query().Get("user")
+		token := r.URL.Query().Get("token")
+		if username == "" || token == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		userID, ok, err := auth(r.Context(), username, token)
+		if err != nil {
+			logger.Log.Debug("cannot verify ws token", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		ws, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			logger.Log.Debug("cannot upgrade to websocket", zap.Error(err))
+			return
+		}
+
+		c := &conn{ws: ws, send: make(chan []byte, sendBuffer)}
+		hub.add(userID, c)
+		defer hub.remove(userID, c)
+
+		go c.writeLoop()
+		c.readLoop()
+	}
+}
+
+// writeLoop доставляет сообщения из буфера и шлёт keepalive-пинги, пока подключение живо.
+func (c *conn) writeLoop() {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	ctx := context.Background()
+	for {
+		select {
+		case payload, ok := <-c.send:
+			if !ok {
+				return
+			}
+			if err := c.ws.Write(ctx, websocket.MessageText, payload); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := c.ws.Ping(ctx); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readLoop вычитывает входящие кадры (клиент ничего не присылает, кроме pong на пинги),
+// пока подключение не закроется.
+func (c *conn) readLoop() {
+	ctx := context.Background()
+	for {
+		if _, _, err := c.ws.Read(ctx); err != nil {
+			return
+		}
+	}
+}
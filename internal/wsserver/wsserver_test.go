@@ -0,0 +1,109 @@
+package wsserver
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/shulganew/alice-skill.git/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHub_Broadcast(t *testing.T) {
+	hub := NewHub()
+	auth := func(ctx context.Context, username, token string) (string, bool, error) {
+		return "ivan", username == "ivan" && token == "valid-token", nil
+	}
+
+	srv := httptest.NewServer(Handler(hub, auth))
+	defer srv.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws?user=ivan&token=valid-token"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ws, _, err := websocket.Dial(ctx, wsURL, nil)
+	require.NoError(t, err)
+	defer ws.Close(websocket.StatusNormalClosure, "")
+
+	// дождёмся, пока подключение зарегистрируется в хабе
+	require.Eventually(t, func() bool {
+		hub.mu.Lock()
+		defer hub.mu.Unlock()
+		return len(hub.conns["ivan"]) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	hub.Broadcast("ivan", store.Message{ID: 1, Sender: "petr", Payload: "привет"})
+
+	var got store.Message
+	require.NoError(t, wsjson.Read(ctx, ws, &got))
+
+	assert.Equal(t, int64(1), got.ID)
+	assert.Equal(t, "привет", got.Payload)
+}
+
+// TestHub_Broadcast_ByInternalUserID воспроизводит реальную цепочку saveAndNotify:
+// подключение аутентифицируется по username, но регистрируется в хабе под внутренним
+// userID, который auth возвращает, а Broadcast вызывается с этим же userID, как это
+// делает store.Message.Recepient (см. cmd/consumer/main.go saveAndNotify).
+func TestHub_Broadcast_ByInternalUserID(t *testing.T) {
+	hub := NewHub()
+	auth := func(ctx context.Context, username, token string) (string, bool, error) {
+		if username != "ivan" || token != "valid-token" {
+			return "", false, nil
+		}
+		return "user-internal-id-42", true, nil
+	}
+
+	srv := httptest.NewServer(Handler(hub, auth))
+	defer srv.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws?user=ivan&token=valid-token"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ws, _, err := websocket.Dial(ctx, wsURL, nil)
+	require.NoError(t, err)
+	defer ws.Close(websocket.StatusNormalClosure, "")
+
+	require.Eventually(t, func() bool {
+		hub.mu.Lock()
+		defer hub.mu.Unlock()
+		return len(hub.conns["user-internal-id-42"]) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// Broadcast, как и saveAndNotify, адресует сообщение по msg.Recepient — внутреннему
+	// userID, а не по username, под которым клиент подключился.
+	hub.Broadcast("user-internal-id-42", store.Message{ID: 2, Sender: "petr", Recepient: "user-internal-id-42", Payload: "привет"})
+
+	var got store.Message
+	require.NoError(t, wsjson.Read(ctx, ws, &got))
+
+	assert.Equal(t, int64(2), got.ID)
+	assert.Equal(t, "привет", got.Payload)
+}
+
+func TestHub_Broadcast_UnauthorizedTokenRejected(t *testing.T) {
+	hub := NewHub()
+	auth := func(ctx context.Context, username, token string) (string, bool, error) {
+		return "", false, nil
+	}
+
+	srv := httptest.NewServer(Handler(hub, auth))
+	defer srv.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws?user=ivan&token=wrong"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, _, err := websocket.Dial(ctx, wsURL, nil)
+	assert.Error(t, err)
+}